@@ -0,0 +1,74 @@
+package wrpc
+
+import (
+	"context"
+	"sync"
+)
+
+// CancelReader wraps r with a single background goroutine that closes
+// r as soon as ctx is done, unblocking a Read or ReadByte that is
+// stalled on a stuck peer. Read and ReadByte themselves always run
+// synchronously in the caller's goroutine and never touch the
+// caller-supplied buffer concurrently with anything else, satisfying
+// the io.Reader contract; a canceled ctx only turns the error the
+// now-closed underlying stream returns into ctx.Err(). Invoker and
+// Server implementations use it to satisfy the Invoke/Serve contract:
+// a caller that cancels ctx, or whose deadline elapses, can always get
+// its goroutine back rather than blocking forever on a silent peer.
+func CancelReader(ctx context.Context, r IndexReadCloser) IndexReadCloser {
+	cr := &cancelReader{ctx: ctx, IndexReadCloser: r, closed: make(chan struct{})}
+	go cr.watch()
+	return cr
+}
+
+type cancelReader struct {
+	ctx context.Context
+	IndexReadCloser
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// watch runs once per cancelReader for its whole lifetime, not once
+// per Read/ReadByte call, so looping reads (e.g. one ReadByte per
+// LEB128 byte) don't each pay for a goroutine.
+func (r *cancelReader) watch() {
+	select {
+	case <-r.ctx.Done():
+		_ = r.IndexReadCloser.Close()
+	case <-r.closed:
+	}
+}
+
+func (r *cancelReader) Read(p []byte) (int, error) {
+	n, err := r.IndexReadCloser.Read(p)
+	if err != nil {
+		if ctxErr := r.ctx.Err(); ctxErr != nil {
+			return n, ctxErr
+		}
+	}
+	return n, err
+}
+
+func (r *cancelReader) ReadByte() (byte, error) {
+	b, err := r.IndexReadCloser.ReadByte()
+	if err != nil {
+		if ctxErr := r.ctx.Err(); ctxErr != nil {
+			return b, ctxErr
+		}
+	}
+	return b, err
+}
+
+func (r *cancelReader) Close() error {
+	r.closeOnce.Do(func() { close(r.closed) })
+	return r.IndexReadCloser.Close()
+}
+
+func (r *cancelReader) Index(path ...uint32) (IndexReadCloser, error) {
+	child, err := r.IndexReadCloser.Index(path...)
+	if err != nil {
+		return nil, err
+	}
+	return CancelReader(r.ctx, child), nil
+}
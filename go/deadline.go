@@ -0,0 +1,31 @@
+package wrpc
+
+import (
+	"context"
+	"time"
+)
+
+// DeadlineHeader is the wRPC header key a client serializes its
+// invocation deadline under (see WithTimeout/WithDeadline), and that a
+// server reads to derive a context for the handler with the same
+// deadline.
+const DeadlineHeader = "wrpc-deadline"
+
+// EncodeDeadline encodes deadline as a DeadlineHeader value.
+func EncodeDeadline(deadline time.Time) []byte {
+	return []byte(deadline.UTC().Format(time.RFC3339Nano))
+}
+
+// DecodeDeadline decodes a DeadlineHeader value produced by
+// EncodeDeadline.
+func DecodeDeadline(b []byte) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, string(b))
+}
+
+// ContextWithDeadline derives a context from ctx carrying deadline.
+// Server implementations call this when dispatching a ServeFunc after
+// decoding a DeadlineHeader, so a handler observes the same deadline
+// the caller set on Invoke.
+func ContextWithDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	return context.WithDeadline(ctx, deadline)
+}
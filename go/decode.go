@@ -0,0 +1,120 @@
+package wrpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// ByteReader is the minimal shape a decoder needs: one byte at a time
+// for LEB128 length prefixes, and bulk reads for the payload that
+// follows.
+type ByteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// ReadString reads a wRPC string off r: a LEB128-encoded length prefix
+// followed by that many bytes of UTF-8, read with io.ReadFull
+// semantics so a short read from the transport does not silently
+// truncate the result. limits.MaxStringLen bounds the decoded length,
+// rejected before a buffer is allocated for it.
+func ReadString(r ByteReader, limits Limits) (string, error) {
+	n, err := readLen(r, limits.maxStringLen())
+	if err != nil {
+		return "", fmt.Errorf("failed to read string length: %w", err)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", fmt.Errorf("failed to read string bytes: %w", err)
+	}
+	if !utf8.Valid(buf) {
+		return string(buf), errors.New("string is not valid UTF-8")
+	}
+	return string(buf), nil
+}
+
+// ReadList reads a wRPC list<T> off r: a LEB128-encoded element count
+// followed by that many elements, each decoded by readElem.
+// limits.MaxListLen bounds the element count, rejected before the
+// backing slice is allocated.
+func ReadList[T any](r ByteReader, limits Limits, readElem func(ByteReader) (T, error)) ([]T, error) {
+	n, err := readLen(r, limits.maxListLen())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read list length: %w", err)
+	}
+	list := make([]T, n)
+	for i := range list {
+		v, err := readElem(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read list element %d: %w", i, err)
+		}
+		list[i] = v
+	}
+	return list, nil
+}
+
+// ReadStream reads a wRPC stream<T> off r as a sequence of
+// length-prefixed chunks terminated by an empty chunk, passing each
+// chunk to sink as it arrives rather than buffering the whole stream
+// in memory. Each chunk's length is bounded by limits.MaxListLen the
+// same way a list's element count is.
+func ReadStream[T any](r ByteReader, limits Limits, readElem func(ByteReader) (T, error), sink func([]T) error) error {
+	for {
+		n, err := readLen(r, limits.maxListLen())
+		if err != nil {
+			return fmt.Errorf("failed to read stream chunk length: %w", err)
+		}
+		if n == 0 {
+			return nil
+		}
+		chunk := make([]T, n)
+		for i := range chunk {
+			v, err := readElem(r)
+			if err != nil {
+				return fmt.Errorf("failed to read stream element %d: %w", i, err)
+			}
+			chunk[i] = v
+		}
+		if err := sink(chunk); err != nil {
+			return err
+		}
+	}
+}
+
+// CopyStream copies a wRPC stream<u8> off r directly onto w, chunk by
+// chunk, without ever buffering the whole stream in memory. It is the
+// io.WriterTo-style fast path ReadStream cannot take for non-byte
+// element types.
+func CopyStream(r ByteReader, limits Limits, w io.Writer) (int64, error) {
+	var total int64
+	for {
+		n, err := readLen(r, limits.maxListLen())
+		if err != nil {
+			return total, fmt.Errorf("failed to read stream chunk length: %w", err)
+		}
+		if n == 0 {
+			return total, nil
+		}
+		written, err := io.CopyN(w, r, int64(n))
+		total += written
+		if err != nil {
+			return total, fmt.Errorf("failed to copy stream chunk: %w", err)
+		}
+	}
+}
+
+// readLen reads a LEB128-encoded 32-bit length prefix, rejecting a
+// length beyond max before the caller allocates anything for it.
+func readLen(r ByteReader, max uint32) (uint32, error) {
+	x, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	if x > uint64(max) {
+		return 0, fmt.Errorf("length %d exceeds limit of %d", x, max)
+	}
+	return uint32(x), nil
+}
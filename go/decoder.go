@@ -0,0 +1,89 @@
+package wrpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Decoder decodes a sequence of wRPC strings off an underlying
+// ByteReader, reusing a scratch buffer across calls instead of
+// allocating a fresh one each time, the same way a bufio.Reader reuses
+// its buffer across Read calls. It is not safe for concurrent use.
+type Decoder struct {
+	r       ByteReader
+	limits  Limits
+	scratch []byte
+}
+
+// NewDecoder returns a Decoder reading from r and bounding decoded
+// strings by limits.
+func NewDecoder(r ByteReader, limits Limits) *Decoder {
+	return &Decoder{r: r, limits: limits}
+}
+
+// ReadString reads a wRPC string, copying it out of the Decoder's
+// reused scratch buffer so the returned string does not alias memory a
+// later call will overwrite.
+func (d *Decoder) ReadString() (string, error) {
+	n, err := readLen(d.r, d.limits.maxStringLen())
+	if err != nil {
+		return "", fmt.Errorf("failed to read string length: %w", err)
+	}
+	d.grow(int(n))
+	buf := d.scratch[:n]
+	if _, err := io.ReadFull(d.r, buf); err != nil {
+		return "", fmt.Errorf("failed to read string bytes: %w", err)
+	}
+	if !utf8.Valid(buf) {
+		return string(buf), errors.New("string is not valid UTF-8")
+	}
+	return string(buf), nil
+}
+
+// WriteStringTo reads a wRPC string and streams it directly onto w in
+// bounded chunks drawn from the Decoder's scratch buffer, without ever
+// holding the whole string in memory at once.
+func (d *Decoder) WriteStringTo(w io.Writer) (int64, error) {
+	n, err := readLen(d.r, d.limits.maxStringLen())
+	if err != nil {
+		return 0, fmt.Errorf("failed to read string length: %w", err)
+	}
+	const maxChunk = 32 * 1024
+	d.grow(minInt(int(n), maxChunk))
+
+	var total int64
+	remaining := int64(n)
+	for remaining > 0 {
+		chunk := d.scratch
+		if int64(len(chunk)) > remaining {
+			chunk = chunk[:remaining]
+		}
+		read, err := io.ReadFull(d.r, chunk)
+		total += int64(read)
+		remaining -= int64(read)
+		if err != nil {
+			return total, fmt.Errorf("failed to read string bytes: %w", err)
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return total, fmt.Errorf("failed to write string bytes: %w", err)
+		}
+	}
+	return total, nil
+}
+
+func (d *Decoder) grow(n int) {
+	if cap(d.scratch) < n {
+		d.scratch = make([]byte, n)
+		return
+	}
+	d.scratch = d.scratch[:n]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+
+	wrpc "wrpc.io/go"
+)
+
+// RoundTripper implements http.RoundTripper over the
+// wasi:http/outgoing-handler `handle` export, letting an *http.Client
+// dispatch requests across a wRPC link. The zero value is not usable;
+// construct one with NewRoundTripper.
+type RoundTripper struct {
+	invoker wrpc.Invoker
+	opts    []wrpc.InvokeOption
+}
+
+// NewRoundTripper returns a RoundTripper that invokes
+// wasi:http/outgoing-handler through invoker.
+func NewRoundTripper(invoker wrpc.Invoker, opts ...wrpc.InvokeOption) *RoundTripper {
+	return &RoundTripper{invoker: invoker, opts: opts}
+}
+
+// RoundTrip encodes req as a wasi:http request record, invokes
+// wasi:http/outgoing-handler, and decodes the resulting record into an
+// *http.Response whose Body streams from the invocation's indexed
+// child stream.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	w, r, err := rt.invoker.Invoke(req.Context(), instanceOutgoingHandler, funcHandle, nil, rt.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invoke `%s`: %w", funcHandle, err)
+	}
+	// wClosed records that w was already closed and rOwned that the
+	// returned *http.Response took ownership of r, so every other return
+	// path below closes both rather than leaking them.
+	wClosed, rOwned := false, false
+	defer func() {
+		if !wClosed {
+			_ = w.Close()
+		}
+		if !rOwned {
+			_ = r.Close()
+		}
+	}()
+
+	scheme := req.URL.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	if err := writeString(w, req.Method); err != nil {
+		return nil, fmt.Errorf("failed to write method: %w", err)
+	}
+	if err := writeString(w, scheme); err != nil {
+		return nil, fmt.Errorf("failed to write scheme: %w", err)
+	}
+	if err := writeString(w, req.URL.Host); err != nil {
+		return nil, fmt.Errorf("failed to write authority: %w", err)
+	}
+	pathWithQuery := req.URL.Path
+	if req.URL.RawQuery != "" {
+		pathWithQuery += "?" + req.URL.RawQuery
+	}
+	if err := writeString(w, pathWithQuery); err != nil {
+		return nil, fmt.Errorf("failed to write path-with-query: %w", err)
+	}
+	if err := writeHeaders(w, headersFromHTTP(req.Header)); err != nil {
+		return nil, fmt.Errorf("failed to write headers: %w", err)
+	}
+
+	body, err := w.Index(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open request body stream: %w", err)
+	}
+	if req.Body != nil {
+		if _, err := io.Copy(body, req.Body); err != nil {
+			return nil, fmt.Errorf("failed to write request body: %w", err)
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close request body stream: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close request stream: %w", err)
+	}
+	wClosed = true
+
+	resp, err := decodeResponse(req, r, rt.invoker.Limits())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	rOwned = true
+	return resp, nil
+}
+
+// decodeResponse reads a wasi:http response record off r: status code,
+// headers, then the body from the record's first indexed child
+// stream. limits bounds every length prefix decoded off r, rejected
+// before a buffer is allocated for it.
+func decodeResponse(req *http.Request, r wrpc.IndexReadCloser, limits wrpc.Limits) (*http.Response, error) {
+	status, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status code: %w", err)
+	}
+	hs, err := readHeaders(r, limits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read headers: %w", err)
+	}
+	bodyReader, err := r.Index(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open response body stream: %w", err)
+	}
+
+	header := make(http.Header, len(hs))
+	for _, h := range hs {
+		header.Add(h.name, string(h.value))
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(int(status))),
+		StatusCode: int(status),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       &responseBody{body: bodyReader, r: r},
+		Request:    req,
+	}, nil
+}
+
+// responseBody is the *http.Response.Body RoundTrip returns. Its Close
+// closes both the response's body child stream and the invocation
+// reader it was indexed from, so a caller that closes the body (as
+// net/http requires) releases the whole invocation rather than
+// leaking the underlying result stream.
+type responseBody struct {
+	body wrpc.IndexReadCloser
+	r    wrpc.IndexReadCloser
+}
+
+func (b *responseBody) Read(p []byte) (int, error) {
+	return b.body.Read(p)
+}
+
+func (b *responseBody) Close() error {
+	err := b.body.Close()
+	if rErr := b.r.Close(); err == nil {
+		err = rErr
+	}
+	return err
+}
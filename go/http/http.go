@@ -0,0 +1,84 @@
+// Package http bridges net/http onto wRPC, implementing both sides of
+// the wasi:http/incoming-handler and wasi:http/outgoing-handler
+// interfaces over a wrpc.Invoker/wrpc.Server pair. It lets existing
+// net/http handlers and clients be placed on a wRPC link without
+// touching generated wasi:http bindings.
+package http
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	wrpc "wrpc.io/go"
+)
+
+const (
+	instanceIncomingHandler = "wasi:http/incoming-handler"
+	instanceOutgoingHandler = "wasi:http/outgoing-handler"
+	funcHandle              = "handle"
+)
+
+// writeString writes s as a wRPC string: a LEB128 length prefix
+// followed by the UTF-8 bytes.
+func writeString(w io.Writer, s string) error {
+	if err := writeUvarint(w, uint64(len(s))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+// writeUvarint writes x as a LEB128-encoded unsigned varint.
+func writeUvarint(w io.Writer, x uint64) error {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+// header is a single wasi:http field-name/field-value pair as carried
+// on the wire; field-value is bytes rather than string since HTTP
+// header values are not required to be valid UTF-8.
+type header struct {
+	name  string
+	value []byte
+}
+
+// writeHeaders writes a list<tuple<string, list<u8>>> of headers.
+func writeHeaders(w io.Writer, hs []header) error {
+	if err := writeUvarint(w, uint64(len(hs))); err != nil {
+		return fmt.Errorf("failed to write header count: %w", err)
+	}
+	for _, h := range hs {
+		if err := writeString(w, h.name); err != nil {
+			return fmt.Errorf("failed to write header name: %w", err)
+		}
+		if err := writeUvarint(w, uint64(len(h.value))); err != nil {
+			return fmt.Errorf("failed to write header value length: %w", err)
+		}
+		if _, err := w.Write(h.value); err != nil {
+			return fmt.Errorf("failed to write header value: %w", err)
+		}
+	}
+	return nil
+}
+
+// readHeaders reads a list<tuple<string, list<u8>>> of headers,
+// rejecting a header count or value length beyond limits before
+// allocating for it.
+func readHeaders(r wrpc.ByteReader, limits wrpc.Limits) ([]header, error) {
+	return wrpc.ReadList(r, limits, func(r wrpc.ByteReader) (header, error) {
+		name, err := wrpc.ReadString(r, limits)
+		if err != nil {
+			return header{}, fmt.Errorf("failed to read header name: %w", err)
+		}
+		value, err := wrpc.ReadList(r, limits, func(r wrpc.ByteReader) (byte, error) {
+			return r.ReadByte()
+		})
+		if err != nil {
+			return header{}, fmt.Errorf("failed to read header %q value: %w", name, err)
+		}
+		return header{name: name, value: value}, nil
+	})
+}
@@ -0,0 +1,219 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	wrpc "wrpc.io/go"
+)
+
+// ServeHTTP registers h as the handler for the wasi:http/incoming-handler
+// `handle` export on w, decoding incoming wasi:http request records into
+// *http.Request (streaming the body through the request's indexed child
+// stream) and encoding h's response back onto the invocation's writer.
+// It returns a function that deregisters the handler.
+func ServeHTTP(w wrpc.Server, h http.Handler, opts ...wrpc.ServeOption) (stop func() error, err error) {
+	limits := w.Limits()
+	return w.Serve(instanceIncomingHandler, funcHandle, func(ctx context.Context, out wrpc.IndexWriteCloser, in wrpc.IndexReadCloser) error {
+		req, err := decodeRequest(ctx, in, limits)
+		if err != nil {
+			return fmt.Errorf("failed to decode request: %w", err)
+		}
+		defer func() {
+			if err := req.Body.Close(); err != nil {
+				slog.ErrorContext(ctx, "failed to close request body", "err", err)
+			}
+		}()
+
+		rw, err := newResponseWriter(out)
+		if err != nil {
+			return fmt.Errorf("failed to open response stream: %w", err)
+		}
+		h.ServeHTTP(rw, req)
+		return rw.finish()
+	}, opts...)
+}
+
+// decodeRequest reconstructs an *http.Request from a wasi:http request
+// record on r: method, scheme, authority and path-with-query as
+// strings, followed by headers, with the body streamed lazily from the
+// record's first indexed child stream. limits bounds every length
+// prefix decoded off r, rejected before a buffer is allocated for it.
+func decodeRequest(ctx context.Context, r wrpc.IndexReadCloser, limits wrpc.Limits) (*http.Request, error) {
+	method, err := wrpc.ReadString(r, limits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read method: %w", err)
+	}
+	scheme, err := wrpc.ReadString(r, limits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scheme: %w", err)
+	}
+	authority, err := wrpc.ReadString(r, limits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authority: %w", err)
+	}
+	pathWithQuery, err := wrpc.ReadString(r, limits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read path-with-query: %w", err)
+	}
+	hs, err := readHeaders(r, limits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read headers: %w", err)
+	}
+
+	u, err := url.Parse(scheme + "://" + authority + pathWithQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse request URI: %w", err)
+	}
+
+	body, err := r.Index(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open request body stream: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), io.NopCloser(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct request: %w", err)
+	}
+	req.Host = authority
+	for _, h := range hs {
+		req.Header.Add(h.name, string(h.value))
+	}
+	return req, nil
+}
+
+// responseWriter implements http.ResponseWriter over an
+// wrpc.IndexWriteCloser, buffering the status line and headers until
+// the first Write (or WriteHeader) call, then streaming the body onto
+// the record's first indexed child stream.
+type responseWriter struct {
+	out         wrpc.IndexWriteCloser
+	body        wrpc.IndexWriteCloser
+	header      http.Header
+	wroteHeader bool
+	err         error
+}
+
+func newResponseWriter(out wrpc.IndexWriteCloser) (*responseWriter, error) {
+	body, err := out.Index(0)
+	if err != nil {
+		return nil, err
+	}
+	return &responseWriter{out: out, body: body, header: make(http.Header)}, nil
+}
+
+func (rw *responseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	rw.err = writeResponsePrologue(rw.out, status, rw.header)
+}
+
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.err != nil {
+		return 0, rw.err
+	}
+	return rw.body.Write(p)
+}
+
+// finish closes the body stream and writes the (possibly empty)
+// trailers onto the record's second indexed child stream.
+func (rw *responseWriter) finish() error {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.err != nil {
+		return rw.err
+	}
+	if err := rw.body.Close(); err != nil {
+		return fmt.Errorf("failed to close response body stream: %w", err)
+	}
+	trailers, err := rw.out.Index(1)
+	if err != nil {
+		return fmt.Errorf("failed to open trailers stream: %w", err)
+	}
+	if err := writeHeaders(trailers, trailerHeaders(rw.header)); err != nil {
+		return fmt.Errorf("failed to write trailers: %w", err)
+	}
+	return trailers.Close()
+}
+
+func writeResponsePrologue(w io.Writer, status int, h http.Header) error {
+	if err := writeUvarint(w, uint64(status)); err != nil {
+		return fmt.Errorf("failed to write status code: %w", err)
+	}
+	if err := writeHeaders(w, headersFromHTTP(h)); err != nil {
+		return fmt.Errorf("failed to write headers: %w", err)
+	}
+	return nil
+}
+
+func headersFromHTTP(h http.Header) []header {
+	declared := declaredTrailers(h)
+	hs := make([]header, 0, len(h))
+	for name, values := range h {
+		if strings.HasPrefix(name, http.TrailerPrefix) {
+			// Deferred trailers live here only until Write/WriteHeader
+			// flushes; trailerHeaders, not the main header block, is
+			// what turns them into wire trailers.
+			continue
+		}
+		if _, ok := declared[name]; ok {
+			// Announced by the Trailer header; trailerHeaders emits it
+			// under its own name from the final header map, so it must
+			// not appear here too.
+			continue
+		}
+		for _, value := range values {
+			hs = append(hs, header{name: name, value: []byte(value)})
+		}
+	}
+	return hs
+}
+
+// declaredTrailers returns the set of canonical header names announced
+// by h's Trailer header.
+func declaredTrailers(h http.Header) map[string]struct{} {
+	declared := make(map[string]struct{})
+	for _, names := range h["Trailer"] {
+		for _, name := range strings.Split(names, ",") {
+			declared[http.CanonicalHeaderKey(strings.TrimSpace(name))] = struct{}{}
+		}
+	}
+	return declared
+}
+
+// trailerHeaders returns the subset of h that are trailers: fields
+// named by the Trailer header (announced up front), plus any field set
+// under the http.TrailerPrefix after the main headers were already
+// written. All other entries in h are ordinary response headers and
+// must not be duplicated here.
+func trailerHeaders(h http.Header) []header {
+	declared := declaredTrailers(h)
+
+	var hs []header
+	for name, values := range h {
+		if strings.HasPrefix(name, http.TrailerPrefix) {
+			name = http.CanonicalHeaderKey(strings.TrimPrefix(name, http.TrailerPrefix))
+		} else if _, ok := declared[name]; !ok {
+			continue
+		}
+		for _, value := range values {
+			hs = append(hs, header{name: name, value: []byte(value)})
+		}
+	}
+	return hs
+}
@@ -0,0 +1,37 @@
+package wrpc
+
+// DefaultMaxStringLen bounds string decoding when a Limits value
+// leaves MaxStringLen unset.
+const DefaultMaxStringLen = 1 << 20 // 1 MiB
+
+// DefaultMaxListLen bounds list decoding when a Limits value leaves
+// MaxListLen unset.
+const DefaultMaxListLen = 1 << 20 // 1Mi elements
+
+// Limits bounds how many bytes a string, or elements a list, a decoder
+// will accept from a peer. ReadString, ReadList and Decoder check the
+// claimed length against these before allocating a buffer for it, so
+// an attacker cannot force a large allocation merely by sending a
+// large length prefix.
+type Limits struct {
+	// MaxStringLen bounds string length in bytes. Zero means
+	// DefaultMaxStringLen.
+	MaxStringLen uint32
+	// MaxListLen bounds the number of elements in a list. Zero means
+	// DefaultMaxListLen.
+	MaxListLen uint32
+}
+
+func (l Limits) maxStringLen() uint32 {
+	if l.MaxStringLen == 0 {
+		return DefaultMaxStringLen
+	}
+	return l.MaxStringLen
+}
+
+func (l Limits) maxListLen() uint32 {
+	if l.MaxListLen == 0 {
+		return DefaultMaxListLen
+	}
+	return l.MaxListLen
+}
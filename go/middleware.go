@@ -0,0 +1,178 @@
+package wrpc
+
+import "context"
+
+// InvokerMiddleware wraps an Invoker, observing or modifying every
+// invocation made through it.
+type InvokerMiddleware interface {
+	WrapInvoker(Invoker) Invoker
+}
+
+// ServerMiddleware wraps a Server, observing or modifying every
+// invocation served through it.
+type ServerMiddleware interface {
+	WrapServer(Server) Server
+}
+
+// WithMiddleware appends mw to the middleware stack of an Invoke call
+// or a Serve registration, outermost first. Each element of mw applies
+// wherever it implements the matching InvokerMiddleware or
+// ServerMiddleware interface, so the same call configures both sides
+// of a link: TracerMiddleware implements both, letting
+// wrpc.WithMiddleware(wrpc.TracerMiddleware{Tracer: t}) instrument an
+// Invoke and a Serve registration identically.
+//
+// WithMiddleware only records the stack in InvokeConfig.Middleware /
+// ServeConfig.Middleware; it does not itself wrap anything. An Invoker
+// or Server implementation must call ApplyInvokerMiddleware /
+// ApplyServerMiddleware (after ApplyInvokeOptions / ApplyServeOptions)
+// to actually fold the stack around the invocation it is about to make
+// or serve.
+func WithMiddleware(mw ...any) interface {
+	InvokeOption
+	ServeOption
+} {
+	return middlewareOption(mw)
+}
+
+type middlewareOption []any
+
+func (o middlewareOption) applyInvoke(c *InvokeConfig) {
+	for _, m := range o {
+		if im, ok := m.(InvokerMiddleware); ok {
+			c.Middleware = append(c.Middleware, im)
+		}
+	}
+}
+
+func (o middlewareOption) applyServe(c *ServeConfig) {
+	for _, m := range o {
+		if sm, ok := m.(ServerMiddleware); ok {
+			c.Middleware = append(c.Middleware, sm)
+		}
+	}
+}
+
+// ApplyInvokerMiddleware folds cfg.Middleware around invoker, outermost
+// first, so the first element of cfg.Middleware is the outermost
+// wrapper an invocation passes through. Invoker implementations call
+// this (after ApplyInvokeOptions) as the one obvious seam for actually
+// applying the middleware stack WithMiddleware recorded, rather than
+// re-deriving the fold themselves.
+func ApplyInvokerMiddleware(invoker Invoker, cfg InvokeConfig) Invoker {
+	for i := len(cfg.Middleware) - 1; i >= 0; i-- {
+		invoker = cfg.Middleware[i].WrapInvoker(invoker)
+	}
+	return invoker
+}
+
+// ApplyServerMiddleware folds cfg.Middleware around server, outermost
+// first, the Server-side counterpart of ApplyInvokerMiddleware.
+func ApplyServerMiddleware(server Server, cfg ServeConfig) Server {
+	for i := len(cfg.Middleware) - 1; i >= 0; i-- {
+		server = cfg.Middleware[i].WrapServer(server)
+	}
+	return server
+}
+
+// TracerMiddleware adapts a Tracer into both an InvokerMiddleware and a
+// ServerMiddleware.
+type TracerMiddleware struct {
+	Tracer Tracer
+}
+
+func (m TracerMiddleware) WrapInvoker(next Invoker) Invoker {
+	return &tracedInvoker{next: next, tracer: m.Tracer}
+}
+
+func (m TracerMiddleware) WrapServer(next Server) Server {
+	return &tracedServer{next: next, tracer: m.Tracer}
+}
+
+type tracedInvoker struct {
+	next   Invoker
+	tracer Tracer
+}
+
+func (i *tracedInvoker) Invoke(ctx context.Context, instance, name string, params []byte, opts ...InvokeOption) (IndexWriteCloser, IndexReadCloser, error) {
+	ctx, done := i.tracer.StartInvoke(ctx, instance, name)
+	w, r, err := i.next.Invoke(ctx, instance, name, params, opts...)
+	if err != nil {
+		done(int64(len(params)), 0, err)
+		return w, r, err
+	}
+	return w, &countingReadCloser{
+		IndexReadCloser: r,
+		onClose: func(n int64, closeErr error) {
+			done(int64(len(params)), n, closeErr)
+		},
+	}, nil
+}
+
+func (i *tracedInvoker) Limits() Limits {
+	return i.next.Limits()
+}
+
+type tracedServer struct {
+	next   Server
+	tracer Tracer
+}
+
+func (s *tracedServer) Serve(instance, name string, f ServeFunc, opts ...ServeOption) (func() error, error) {
+	return s.next.Serve(instance, name, func(ctx context.Context, w IndexWriteCloser, r IndexReadCloser) error {
+		ctx, done := s.tracer.StartServe(ctx, instance, name)
+		cr := &countingReadCloser{IndexReadCloser: r}
+		cw := &countingWriteCloser{IndexWriteCloser: w}
+		err := f(ctx, cw, cr)
+		done(cr.n, cw.n, err)
+		return err
+	}, opts...)
+}
+
+func (s *tracedServer) Limits() Limits {
+	return s.next.Limits()
+}
+
+// countingReadCloser wraps an IndexReadCloser, counting the bytes read
+// through it and reporting the total (along with the Close error, if
+// any) through onClose.
+type countingReadCloser struct {
+	IndexReadCloser
+	n       int64
+	onClose func(n int64, err error)
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.IndexReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReadCloser) ReadByte() (byte, error) {
+	b, err := c.IndexReadCloser.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+func (c *countingReadCloser) Close() error {
+	err := c.IndexReadCloser.Close()
+	if c.onClose != nil {
+		c.onClose(c.n, err)
+	}
+	return err
+}
+
+// countingWriteCloser wraps an IndexWriteCloser, counting the bytes
+// written through it.
+type countingWriteCloser struct {
+	IndexWriteCloser
+	n int64
+}
+
+func (c *countingWriteCloser) Write(p []byte) (int, error) {
+	n, err := c.IndexWriteCloser.Write(p)
+	c.n += int64(n)
+	return n, err
+}
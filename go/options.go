@@ -0,0 +1,86 @@
+package wrpc
+
+import "time"
+
+// InvokeConfig holds the effective configuration for a single Invoke
+// call once its InvokeOptions have been applied. Invoker
+// implementations call ApplyInvokeOptions to obtain it before making
+// the call.
+type InvokeConfig struct {
+	// Deadline, if non-zero, is serialized onto the wire as
+	// DeadlineHeader so the server derives a context with the same
+	// deadline the caller set with WithTimeout or WithDeadline.
+	Deadline time.Time
+
+	// Middleware wraps the Invoker making this call, outermost first.
+	// Pass it to ApplyInvokerMiddleware to actually apply the stack;
+	// this package never calls WrapInvoker on its own.
+	Middleware []InvokerMiddleware
+}
+
+// InvokeOption configures a single Invoke call. Some options, such as
+// WithMiddleware, also implement ServeOption and so can configure a
+// Serve registration with the same call.
+type InvokeOption interface {
+	applyInvoke(*InvokeConfig)
+}
+
+// ApplyInvokeOptions builds the InvokeConfig for opts. Invoker
+// implementations call this to get a concrete configuration to act on,
+// then call ApplyInvokerMiddleware with the result so WithMiddleware
+// options actually take effect.
+func ApplyInvokeOptions(opts ...InvokeOption) InvokeConfig {
+	var cfg InvokeConfig
+	for _, opt := range opts {
+		opt.applyInvoke(&cfg)
+	}
+	return cfg
+}
+
+type invokeOptionFunc func(*InvokeConfig)
+
+func (f invokeOptionFunc) applyInvoke(c *InvokeConfig) { f(c) }
+
+// WithTimeout sets the invocation's deadline to d from now. It is a
+// convenience for the common case of WithDeadline(time.Now().Add(d)).
+func WithTimeout(d time.Duration) InvokeOption {
+	return WithDeadline(time.Now().Add(d))
+}
+
+// WithDeadline sets the invocation's deadline.
+func WithDeadline(deadline time.Time) InvokeOption {
+	return invokeOptionFunc(func(c *InvokeConfig) {
+		c.Deadline = deadline
+	})
+}
+
+// ServeConfig holds the effective configuration for a single Serve
+// registration once its ServeOptions have been applied.
+type ServeConfig struct {
+	// Middleware wraps the Server serving this registration, outermost
+	// first. Pass it to ApplyServerMiddleware to actually apply the
+	// stack; this package never calls WrapServer on its own.
+	Middleware []ServerMiddleware
+}
+
+// ServeOption configures a single Serve registration. Some options,
+// such as WithMiddleware, also implement InvokeOption and so can
+// configure an Invoke call with the same call.
+type ServeOption interface {
+	applyServe(*ServeConfig)
+}
+
+// ApplyServeOptions builds the ServeConfig for opts. Server
+// implementations call ApplyServerMiddleware with the result so
+// WithMiddleware options actually take effect.
+func ApplyServeOptions(opts ...ServeOption) ServeConfig {
+	var cfg ServeConfig
+	for _, opt := range opts {
+		opt.applyServe(&cfg)
+	}
+	return cfg
+}
+
+type serveOptionFunc func(*ServeConfig)
+
+func (f serveOptionFunc) applyServe(c *ServeConfig) { f(c) }
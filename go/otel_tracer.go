@@ -0,0 +1,84 @@
+package wrpc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OTelTracer is a Tracer that starts an OpenTelemetry span named
+// "instance/name" for every invocation, recording the parameter/result
+// byte counts as span attributes.
+type OTelTracer struct {
+	// Tracer is the OpenTelemetry tracer spans are started from. If
+	// nil, otel.Tracer("wrpc.io/go") is used.
+	Tracer trace.Tracer
+}
+
+func (t OTelTracer) tracer() trace.Tracer {
+	if t.Tracer != nil {
+		return t.Tracer
+	}
+	return otel.Tracer("wrpc.io/go")
+}
+
+// StartInvoke implements Tracer.
+func (t OTelTracer) StartInvoke(ctx context.Context, instance, name string) (context.Context, func(int64, int64, error)) {
+	ctx, span := t.tracer().Start(ctx, instance+"/"+name, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx, func(paramBytes, resultBytes int64, err error) {
+		finishSpan(span, paramBytes, resultBytes, err)
+	}
+}
+
+// StartServe implements Tracer.
+func (t OTelTracer) StartServe(ctx context.Context, instance, name string) (context.Context, func(int64, int64, error)) {
+	ctx, span := t.tracer().Start(ctx, instance+"/"+name, trace.WithSpanKind(trace.SpanKindServer))
+	return ctx, func(paramBytes, resultBytes int64, err error) {
+		finishSpan(span, paramBytes, resultBytes, err)
+	}
+}
+
+func finishSpan(span trace.Span, paramBytes, resultBytes int64, err error) {
+	span.SetAttributes(
+		attribute.Int64("wrpc.param_bytes", paramBytes),
+		attribute.Int64("wrpc.result_bytes", resultBytes),
+	)
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// InjectTraceparent writes the W3C traceparent (and tracestate, if
+// any) for ctx's span into headers, for a client to carry across the
+// wRPC framing alongside the invocation's other headers.
+func InjectTraceparent(ctx context.Context, headers map[string][]byte) {
+	propagation.TraceContext{}.Inject(ctx, headerCarrier(headers))
+}
+
+// ExtractTraceparent derives a context from ctx carrying the remote
+// span described by a traceparent (and tracestate) previously written
+// by InjectTraceparent, for a server to use as the parent of its own
+// span.
+func ExtractTraceparent(ctx context.Context, headers map[string][]byte) context.Context {
+	return propagation.TraceContext{}.Extract(ctx, headerCarrier(headers))
+}
+
+// headerCarrier adapts the string->[]byte headers wRPC carries on the
+// wire to OpenTelemetry's propagation.TextMapCarrier.
+type headerCarrier map[string][]byte
+
+func (c headerCarrier) Get(key string) string { return string(c[key]) }
+func (c headerCarrier) Set(key, value string) { c[key] = []byte(value) }
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+var _ propagation.TextMapCarrier = headerCarrier(nil)
@@ -0,0 +1,46 @@
+package wrpc
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogTracer is a Tracer that logs one structured line per invocation
+// through log/slog, replacing generated bindings' former per-byte
+// slog.Debug calls with a single summary carrying the instance,
+// function name, and parameter/result byte counts.
+type SlogTracer struct {
+	// Logger is used to log invocations. If nil, slog.Default() is
+	// used.
+	Logger *slog.Logger
+}
+
+func (t SlogTracer) logger() *slog.Logger {
+	if t.Logger != nil {
+		return t.Logger
+	}
+	return slog.Default()
+}
+
+// StartInvoke implements Tracer.
+func (t SlogTracer) StartInvoke(ctx context.Context, instance, name string) (context.Context, func(int64, int64, error)) {
+	return ctx, func(paramBytes, resultBytes int64, err error) {
+		t.log(ctx, "invoke", instance, name, paramBytes, resultBytes, err)
+	}
+}
+
+// StartServe implements Tracer.
+func (t SlogTracer) StartServe(ctx context.Context, instance, name string) (context.Context, func(int64, int64, error)) {
+	return ctx, func(paramBytes, resultBytes int64, err error) {
+		t.log(ctx, "serve", instance, name, paramBytes, resultBytes, err)
+	}
+}
+
+func (t SlogTracer) log(ctx context.Context, kind, instance, name string, paramBytes, resultBytes int64, err error) {
+	attrs := []any{"instance", instance, "name", name, "param_bytes", paramBytes, "result_bytes", resultBytes}
+	if err != nil {
+		t.logger().ErrorContext(ctx, "wrpc "+kind+" failed", append(attrs, "err", err)...)
+		return
+	}
+	t.logger().DebugContext(ctx, "wrpc "+kind+" completed", attrs...)
+}
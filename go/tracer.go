@@ -0,0 +1,18 @@
+package wrpc
+
+import "context"
+
+// Tracer observes wRPC invocations. It is the pluggable seam generated
+// bindings and core middleware use instead of logging every byte read
+// or written directly, so users can swap in structured spans or
+// metric counters without editing generated code.
+type Tracer interface {
+	// StartInvoke is called before an outgoing invocation of `name`
+	// exported by `instance` begins. It returns a context derived from
+	// ctx (for example, carrying a new span) and a function to call
+	// with the invocation's outcome once it completes.
+	StartInvoke(ctx context.Context, instance, name string) (context.Context, func(paramBytes, resultBytes int64, err error))
+
+	// StartServe is the Serve-side counterpart of StartInvoke.
+	StartServe(ctx context.Context, instance, name string) (context.Context, func(paramBytes, resultBytes int64, err error))
+}
@@ -0,0 +1,64 @@
+// Package wrpc provides the core client and server primitives used by
+// wit-bindgen-wrpc-go generated bindings: typed RPC built on top of
+// trees of indexable byte streams.
+package wrpc
+
+import (
+	"context"
+	"io"
+)
+
+// IndexReadCloser is a readable byte stream that can be indexed into
+// nested result streams, mirroring the tree shape of a wRPC value (for
+// example, the stream backing a `list<u8>` nested inside a result
+// record is reached via Index on the record's own stream).
+type IndexReadCloser interface {
+	io.Reader
+	io.ByteReader
+	io.Closer
+
+	// Index returns the reader for the nested stream at path, creating
+	// it on first use. Each element of path selects a child of the
+	// previously indexed stream.
+	Index(path ...uint32) (IndexReadCloser, error)
+}
+
+// IndexWriteCloser is the write-side counterpart of IndexReadCloser.
+type IndexWriteCloser interface {
+	io.Writer
+	io.Closer
+
+	// Index returns the writer for the nested stream at path, creating
+	// it on first use.
+	Index(path ...uint32) (IndexWriteCloser, error)
+}
+
+// Invoker performs outgoing wRPC invocations.
+type Invoker interface {
+	// Invoke calls the function `name` exported by `instance`. params
+	// is written eagerly before the call returns; callers that need to
+	// stream parameters instead should pass nil and write them onto the
+	// returned IndexWriteCloser. The returned reader carries the
+	// result.
+	Invoke(ctx context.Context, instance, name string, params []byte, opts ...InvokeOption) (IndexWriteCloser, IndexReadCloser, error)
+
+	// Limits bounds the strings and lists this Invoker will decode out
+	// of invocation results, so generated bindings can reject an
+	// oversized length prefix before allocating a buffer for it.
+	Limits() Limits
+}
+
+// ServeFunc handles a single incoming invocation of an exported
+// function.
+type ServeFunc func(ctx context.Context, w IndexWriteCloser, r IndexReadCloser) error
+
+// Server accepts incoming wRPC invocations.
+type Server interface {
+	// Serve registers f as the handler for `name` exported by
+	// `instance`, returning a function that deregisters it.
+	Serve(instance, name string, f ServeFunc, opts ...ServeOption) (stop func() error, err error)
+
+	// Limits bounds the strings and lists this Server will decode out
+	// of invocation parameters.
+	Limits() Limits
+}